@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// inflightCall tracks a loader call in progress for a single key, so
+// concurrent callers can wait on it instead of invoking the loader again.
+type inflightCall[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+}
+
+// peek returns the item stored under key, if any, along with whether it has
+// passed its expiration. Unlike Get, it does not evict expired items, so
+// GetOrLoadSWR can still hand the stale value to callers while refreshing it.
+func (c *Cache[K, V]) peek(key K) (value V, found bool, expired bool) {
+	c.mu.RLock()
+	item, found := c.items[key]
+	c.mu.RUnlock()
+	if !found {
+		var zero V
+		return zero, false, false
+	}
+	expired = item.expiration > 0 && time.Now().UnixNano() > item.expiration
+	return item.value, true, expired
+}
+
+// GetOrLoad returns the cached value for key if present, otherwise invokes
+// loader to populate it with the given ttl. Concurrent calls for the same
+// missing key coalesce: loader runs exactly once and every caller receives
+// its result.
+func (c *Cache[K, V]) GetOrLoad(key K, ttl time.Duration, loader func() (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	return c.load(key, ttl, loader)
+}
+
+// Refresh unconditionally re-runs loader for key and stores its result with
+// ttl, coalescing with any other in-flight load for the same key.
+func (c *Cache[K, V]) Refresh(key K, ttl time.Duration, loader func() (V, error)) error {
+	_, err := c.load(key, ttl, loader)
+	return err
+}
+
+// GetOrLoadSWR implements stale-while-revalidate: an expired value is
+// returned immediately while loader reloads it in the background. A cache
+// miss falls back to GetOrLoad's blocking behavior since there is nothing
+// stale to serve yet.
+func (c *Cache[K, V]) GetOrLoadSWR(key K, ttl time.Duration, loader func() (V, error)) (V, error) {
+	value, found, expired := c.peek(key)
+	if found && !expired {
+		return value, nil
+	}
+	if found && expired {
+		go c.Refresh(key, ttl, loader)
+		return value, nil
+	}
+	return c.GetOrLoad(key, ttl, loader)
+}
+
+// load runs loader for key, coalescing concurrent calls so it executes at
+// most once at a time per key, and stores a successful result with ttl.
+func (c *Cache[K, V]) load(key K, ttl time.Duration, loader func() (V, error)) (V, error) {
+	c.callMu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.callMu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err
+	}
+	call := &inflightCall[V]{}
+	call.wg.Add(1)
+	c.calls[key] = call
+	c.callMu.Unlock()
+
+	call.val, call.err = loader()
+	if call.err == nil {
+		c.Set(key, call.val, ttl)
+	}
+	call.wg.Done()
+
+	c.callMu.Lock()
+	delete(c.calls, key)
+	c.callMu.Unlock()
+
+	return call.val, call.err
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheGetOrLoadCoalesces(t *testing.T) {
+	c := NewCache[string, int]()
+	defer c.Stop()
+
+	var calls int32
+	loader := func() (int, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return 42, nil
+	}
+
+	const callers = 20
+	results := make([]int, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad("key", 0, loader)
+			if err != nil {
+				t.Errorf("GetOrLoad: %v", err)
+				return
+			}
+			results[i] = v
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("loader called %d times, want exactly 1", got)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("results[%d] = %d, want 42", i, v)
+		}
+	}
+}
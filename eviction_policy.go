@@ -0,0 +1,237 @@
+package main
+
+import "container/list"
+
+// EvictionPolicy selects the strategy NewCacheWithPolicy uses to pick a
+// victim once the cache grows past its configured maxItems.
+type EvictionPolicy int
+
+const (
+	// PolicyLRU evicts the least recently used item.
+	PolicyLRU EvictionPolicy = iota
+	// PolicyLFU evicts the least frequently used item.
+	PolicyLFU
+	// PolicyFIFO evicts the oldest inserted item, regardless of access pattern.
+	PolicyFIFO
+)
+
+// EvictionReason tells an OnEvicted callback why an item left the cache.
+type EvictionReason int
+
+const (
+	// EvictionReasonExpired means the item was removed because its TTL elapsed.
+	EvictionReasonExpired EvictionReason = iota
+	// EvictionReasonSize means the item was removed to keep the cache within maxItems.
+	EvictionReasonSize
+)
+
+// evictionOrder tracks insertion/access order for a bounded Cache and
+// decides which key to evict next. Implementations are not safe for
+// concurrent use; callers must hold the owning Cache's mu.
+type evictionOrder[K comparable] interface {
+	// add records a brand new key.
+	add(key K)
+	// touch records an access (Get, or Set of an existing key).
+	touch(key K)
+	// remove drops all bookkeeping for key, e.g. after an explicit Delete.
+	remove(key K)
+	// evict picks a victim, removes its bookkeeping, and returns it.
+	evict() (K, bool)
+}
+
+// NewCacheWithPolicy creates a Cache bounded to maxItems entries. Once an
+// insertion would push the cache past maxItems, one item is evicted
+// according to policy and reported through OnEvicted, if set.
+func NewCacheWithPolicy[K comparable, V any](maxItems int, policy EvictionPolicy) *Cache[K, V] {
+	c := NewCache[K, V]()
+	c.maxItems = maxItems
+	switch policy {
+	case PolicyLFU:
+		c.order = newLFUOrder[K]()
+	case PolicyFIFO:
+		c.order = newFIFOOrder[K]()
+	default:
+		c.order = newLRUOrder[K]()
+	}
+	return c
+}
+
+// lruOrder evicts the least recently used key, using a doubly-linked list
+// where the most recently touched key sits at the front.
+type lruOrder[K comparable] struct {
+	ll    *list.List
+	elems map[K]*list.Element
+}
+
+func newLRUOrder[K comparable]() *lruOrder[K] {
+	return &lruOrder[K]{ll: list.New(), elems: make(map[K]*list.Element)}
+}
+
+func (o *lruOrder[K]) add(key K) {
+	o.elems[key] = o.ll.PushFront(key)
+}
+
+func (o *lruOrder[K]) touch(key K) {
+	if elem, ok := o.elems[key]; ok {
+		o.ll.MoveToFront(elem)
+	}
+}
+
+func (o *lruOrder[K]) remove(key K) {
+	if elem, ok := o.elems[key]; ok {
+		o.ll.Remove(elem)
+		delete(o.elems, key)
+	}
+}
+
+func (o *lruOrder[K]) evict() (K, bool) {
+	back := o.ll.Back()
+	if back == nil {
+		var zero K
+		return zero, false
+	}
+	key := back.Value.(K)
+	o.ll.Remove(back)
+	delete(o.elems, key)
+	return key, true
+}
+
+// fifoOrder evicts the oldest inserted key, ignoring access pattern.
+type fifoOrder[K comparable] struct {
+	ll    *list.List
+	elems map[K]*list.Element
+}
+
+func newFIFOOrder[K comparable]() *fifoOrder[K] {
+	return &fifoOrder[K]{ll: list.New(), elems: make(map[K]*list.Element)}
+}
+
+func (o *fifoOrder[K]) add(key K) {
+	o.elems[key] = o.ll.PushFront(key)
+}
+
+// touch is a no-op: FIFO ordering is unaffected by access pattern.
+func (o *fifoOrder[K]) touch(key K) {}
+
+func (o *fifoOrder[K]) remove(key K) {
+	if elem, ok := o.elems[key]; ok {
+		o.ll.Remove(elem)
+		delete(o.elems, key)
+	}
+}
+
+func (o *fifoOrder[K]) evict() (K, bool) {
+	back := o.ll.Back()
+	if back == nil {
+		var zero K
+		return zero, false
+	}
+	key := back.Value.(K)
+	o.ll.Remove(back)
+	delete(o.elems, key)
+	return key, true
+}
+
+// lfuOrder evicts the least frequently used key in O(1), using the
+// frequency-bucket technique (Ketan Shah / Anirban Ghosh): each bucket is a
+// list of keys sharing an access count, buckets are indexed by that count,
+// and minFreq tracks the lowest non-empty bucket. maxFreq bounds how far
+// advanceMinFreq ever has to walk forward to find the next one.
+type lfuOrder[K comparable] struct {
+	freqOf  map[K]int
+	buckets map[int]*list.List
+	elems   map[K]*list.Element
+	minFreq int
+	maxFreq int
+}
+
+func newLFUOrder[K comparable]() *lfuOrder[K] {
+	return &lfuOrder[K]{
+		freqOf:  make(map[K]int),
+		buckets: make(map[int]*list.List),
+		elems:   make(map[K]*list.Element),
+	}
+}
+
+func (o *lfuOrder[K]) bucket(freq int) *list.List {
+	b, ok := o.buckets[freq]
+	if !ok {
+		b = list.New()
+		o.buckets[freq] = b
+	}
+	return b
+}
+
+// advanceMinFreq walks minFreq forward to the next bucket that still holds a
+// key, for use whenever a removal (evict or remove) drains the bucket
+// minFreq currently points at. touch doesn't need this: the key it moves
+// always lands in freq+1, so bumping minFreq by one is enough there.
+func (o *lfuOrder[K]) advanceMinFreq() {
+	for freq := o.minFreq + 1; freq <= o.maxFreq; freq++ {
+		if b, ok := o.buckets[freq]; ok && b.Len() > 0 {
+			o.minFreq = freq
+			return
+		}
+	}
+}
+
+func (o *lfuOrder[K]) add(key K) {
+	o.freqOf[key] = 1
+	o.elems[key] = o.bucket(1).PushFront(key)
+	o.minFreq = 1
+	if o.maxFreq < 1 {
+		o.maxFreq = 1
+	}
+}
+
+// touch is a no-op for a key that isn't tracked, matching lruOrder.touch:
+// Cache.Get briefly releases mu before re-acquiring it to call touch, and a
+// concurrent Delete/expire can remove the key in that window. Re-adding it
+// here would resurrect bookkeeping for an item no longer in c.items.
+func (o *lfuOrder[K]) touch(key K) {
+	freq, ok := o.freqOf[key]
+	if !ok {
+		return
+	}
+	o.bucket(freq).Remove(o.elems[key])
+	if o.bucket(freq).Len() == 0 && o.minFreq == freq {
+		o.minFreq++
+	}
+	freq++
+	if freq > o.maxFreq {
+		o.maxFreq = freq
+	}
+	o.freqOf[key] = freq
+	o.elems[key] = o.bucket(freq).PushFront(key)
+}
+
+func (o *lfuOrder[K]) remove(key K) {
+	freq, ok := o.freqOf[key]
+	if !ok {
+		return
+	}
+	b := o.bucket(freq)
+	b.Remove(o.elems[key])
+	delete(o.elems, key)
+	delete(o.freqOf, key)
+	if b.Len() == 0 && o.minFreq == freq {
+		o.advanceMinFreq()
+	}
+}
+
+func (o *lfuOrder[K]) evict() (K, bool) {
+	b, ok := o.buckets[o.minFreq]
+	if !ok || b.Len() == 0 {
+		var zero K
+		return zero, false
+	}
+	back := b.Back()
+	key := back.Value.(K)
+	b.Remove(back)
+	delete(o.elems, key)
+	delete(o.freqOf, key)
+	if b.Len() == 0 {
+		o.advanceMinFreq()
+	}
+	return key, true
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestLFUPolicyStaysWithinBoundUnderConcurrentGetDelete guards against the
+// phantom-entry bug where lfuOrder.touch re-added bookkeeping for a key a
+// concurrent Delete had just removed, letting Set's eviction silently fail
+// to shrink the cache back to maxItems.
+func TestLFUPolicyStaysWithinBoundUnderConcurrentGetDelete(t *testing.T) {
+	const maxItems = 5
+	c := NewCacheWithPolicy[string, int](maxItems, PolicyLFU)
+	defer c.Stop()
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for i, k := range keys {
+		c.Set(k, i, 0)
+	}
+
+	var wg sync.WaitGroup
+	for _, k := range keys {
+		k := k
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Get(k)
+		}()
+		go func() {
+			defer wg.Done()
+			c.Delete(k)
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < maxItems*2; i++ {
+		c.Set(fmt.Sprintf("new%d", i), i, 0)
+	}
+
+	if got := c.Len(); got > maxItems {
+		t.Fatalf("cache exceeded maxItems bound: got %d, want <= %d", got, maxItems)
+	}
+}
+
+// TestLFUOrderEvictAdvancesPastDrainedBucket guards against evict failing to
+// advance minFreq once it drains the bucket minFreq points at: after "b" (the
+// only key at freq 1) is evicted, "a" (freq 2) must still be found even
+// though the freq-1 bucket is now empty.
+func TestLFUOrderEvictAdvancesPastDrainedBucket(t *testing.T) {
+	o := newLFUOrder[string]()
+	o.add("a")
+	o.touch("a")
+	o.add("b")
+
+	if key, ok := o.evict(); !ok || key != "b" {
+		t.Fatalf("evict() = %q, %v; want \"b\", true", key, ok)
+	}
+	if key, ok := o.evict(); !ok || key != "a" {
+		t.Fatalf("evict() = %q, %v; want \"a\", true", key, ok)
+	}
+	if _, ok := o.evict(); ok {
+		t.Fatal("evict() on empty order should return ok=false")
+	}
+}
@@ -1,107 +1,282 @@
-package main
-
-import (
-	"fmt"
-	"sync"
-	"time"
-)
-
-// CacheItem represents a single cache item
-type CacheItem struct {
-	value      interface{}
-	expiration int64
-}
-
-// Cache represents the in-memory cache
-type Cache struct {
-	items map[string]*CacheItem
-	mu    sync.RWMutex
-}
-
-// NewCache creates a new Cache instance
-func NewCache() *Cache {
-	cache := &Cache{
-		items: make(map[string]*CacheItem),
-	}
-	go cache.startEviction()
-	return cache
-}
-
-// Set adds a new item to the cache with an optional expiration duration
-func (c *Cache) Set(key string, value interface{}, duration time.Duration) {
-	var expiration int64
-	if duration > 0 {
-		expiration = time.Now().Add(duration).UnixNano()
-	}
-	c.mu.Lock()
-	c.items[key] = &CacheItem{
-		value:      value,
-		expiration: expiration,
-	}
-	c.mu.Unlock()
-}
-
-// Get retrieves an item from the cache
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	item, found := c.items[key]
-	c.mu.RUnlock()
-	if !found {
-		return nil, false
-	}
-	if item.expiration > 0 && time.Now().UnixNano() > item.expiration {
-		c.Delete(key)
-		return nil, false
-	}
-	return item.value, true
-}
-
-// Delete removes an item from the cache
-func (c *Cache) Delete(key string) {
-	c.mu.Lock()
-	delete(c.items, key)
-	c.mu.Unlock()
-}
-
-// startEviction runs a goroutine to periodically clean up expired items
-func (c *Cache) startEviction() {
-	for {
-		time.Sleep(1 * time.Minute)
-		now := time.Now().UnixNano()
-		c.mu.Lock()
-		for key, item := range c.items {
-			if item.expiration > 0 && now > item.expiration {
-				delete(c.items, key)
-			}
-		}
-		c.mu.Unlock()
-	}
-}
-
-func main() {
-	cache := NewCache()
-	cache.Set("key1", "value1", 5*time.Second)
-	cache.Set("key2", "value2", 0) // no expiration
-
-	value, found := cache.Get("key1")
-	if found {
-		fmt.Println("key1:", value)
-	} else {
-		fmt.Println("key1 not found")
-	}
-
-	time.Sleep(6 * time.Second)
-	value, found = cache.Get("key1")
-	if found {
-		fmt.Println("key1:", value)
-	} else {
-		fmt.Println("key1 not found after expiration")
-	}
-
-	value, found = cache.Get("key2")
-	if found {
-		fmt.Println("key2:", value)
-	} else {
-		fmt.Println("key2 not found")
-	}
-}
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CacheItem represents a single cache item
+type CacheItem[V any] struct {
+	value      V
+	expiration int64
+}
+
+// Cache represents the in-memory cache. K is the key type and V the value
+// type; using generics here avoids the interface{} boxing and type
+// assertions a map[string]interface{} based cache would require.
+type Cache[K comparable, V any] struct {
+	items map[K]*CacheItem[V]
+	mu    sync.RWMutex
+	done  chan struct{}
+
+	// maxItems and order are non-zero/non-nil only when the cache was
+	// created via NewCacheWithPolicy; a plain NewCache cache grows
+	// unbounded and only ever evicts on TTL.
+	maxItems  int
+	order     evictionOrder[K]
+	onEvicted func(key K, value V, reason EvictionReason)
+
+	// wal is non-nil only for caches created via NewCacheWithWAL; every
+	// Set/Delete is appended to it so the cache can be replayed on restart.
+	wal   *os.File
+	walMu sync.Mutex
+
+	// calls coalesces concurrent GetOrLoad/Refresh calls for the same key
+	// so a loader only ever runs once at a time per key.
+	callMu sync.Mutex
+	calls  map[K]*inflightCall[V]
+}
+
+// AnyCache is a Cache keyed by string that stores arbitrary values,
+// kept for backward compatibility with callers that relied on the
+// pre-generics interface{}-based API.
+type AnyCache = Cache[string, any]
+
+// NewCache creates a new Cache instance
+func NewCache[K comparable, V any]() *Cache[K, V] {
+	cache := &Cache[K, V]{
+		items: make(map[K]*CacheItem[V]),
+		done:  make(chan struct{}),
+		calls: make(map[K]*inflightCall[V]),
+	}
+	go cache.startEviction()
+	return cache
+}
+
+// Set adds a new item to the cache with an optional expiration duration. If
+// the cache was created with a bounded policy and this insertion pushes it
+// past maxItems, the policy's victim is evicted and reported via onEvicted.
+func (c *Cache[K, V]) Set(key K, value V, duration time.Duration) {
+	var expiration int64
+	if duration > 0 {
+		expiration = time.Now().Add(duration).UnixNano()
+	}
+	c.mu.Lock()
+	_, exists := c.items[key]
+	c.items[key] = &CacheItem[V]{
+		value:      value,
+		expiration: expiration,
+	}
+	if c.order != nil {
+		if exists {
+			c.order.touch(key)
+		} else {
+			c.order.add(key)
+		}
+	}
+	evicted := c.evictExcessLocked()
+	// Appended while still holding mu so WAL order always matches the
+	// order map mutations are applied in, even under concurrent Set/Delete
+	// calls for the same key.
+	if c.wal != nil {
+		c.appendWAL(walOpSet, key, value, expiration)
+	}
+	c.mu.Unlock()
+	c.reportEvicted(evicted, EvictionReasonSize)
+}
+
+// Get retrieves an item from the cache
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	item, found := c.items[key]
+	c.mu.RUnlock()
+	if !found {
+		var zero V
+		return zero, false
+	}
+	if item.expiration > 0 && time.Now().UnixNano() > item.expiration {
+		c.expire(key)
+		var zero V
+		return zero, false
+	}
+	if c.order != nil {
+		c.mu.Lock()
+		c.order.touch(key)
+		c.mu.Unlock()
+	}
+	return item.value, true
+}
+
+// Delete removes an item from the cache
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	delete(c.items, key)
+	if c.order != nil {
+		c.order.remove(key)
+	}
+	// Appended while still holding mu; see the matching comment in Set.
+	if c.wal != nil {
+		var zero V
+		c.appendWAL(walOpDelete, key, zero, 0)
+	}
+	c.mu.Unlock()
+}
+
+// evictedEntry records a key/value pair evicted while c.mu was held, so the
+// onEvicted callback can be invoked once the lock is released.
+type evictedEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// evictExcessLocked evicts items via c.order until the cache is back within
+// maxItems, assuming the caller already holds c.mu. It's used both by Set,
+// which can only ever overflow by the single item it just inserted, and by
+// Load/replayWAL, which can bulk-insert many items past the bound at once.
+func (c *Cache[K, V]) evictExcessLocked() []evictedEntry[K, V] {
+	if c.maxItems <= 0 {
+		return nil
+	}
+	var evicted []evictedEntry[K, V]
+	for len(c.items) > c.maxItems {
+		victim, ok := c.order.evict()
+		if !ok {
+			break
+		}
+		item, found := c.items[victim]
+		delete(c.items, victim)
+		if found {
+			evicted = append(evicted, evictedEntry[K, V]{key: victim, value: item.value})
+		}
+	}
+	return evicted
+}
+
+// reportEvicted invokes onEvicted for each entry, if set. Call it after
+// releasing c.mu.
+func (c *Cache[K, V]) reportEvicted(entries []evictedEntry[K, V], reason EvictionReason) {
+	if c.onEvicted == nil {
+		return
+	}
+	for _, e := range entries {
+		c.onEvicted(e.key, e.value, reason)
+	}
+}
+
+// expire removes a TTL-expired item and reports it via onEvicted.
+func (c *Cache[K, V]) expire(key K) {
+	c.mu.Lock()
+	item, found := c.items[key]
+	if !found {
+		c.mu.Unlock()
+		return
+	}
+	delete(c.items, key)
+	if c.order != nil {
+		c.order.remove(key)
+	}
+	c.mu.Unlock()
+	if c.onEvicted != nil {
+		c.onEvicted(key, item.value, EvictionReasonExpired)
+	}
+}
+
+// startEviction runs a goroutine to periodically clean up expired items
+func (c *Cache[K, V]) startEviction() {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now().UnixNano()
+			c.mu.Lock()
+			var expired []K
+			for key, item := range c.items {
+				if item.expiration > 0 && now > item.expiration {
+					expired = append(expired, key)
+				}
+			}
+			c.mu.Unlock()
+			for _, key := range expired {
+				c.expire(key)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// Stop terminates the cache's eviction goroutine. Call it when the cache
+// is no longer needed to avoid leaking the underlying ticker.
+func (c *Cache[K, V]) Stop() {
+	close(c.done)
+}
+
+// Items returns a snapshot of every non-expired value currently in the cache, keyed as stored.
+func (c *Cache[K, V]) Items() map[K]V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := time.Now().UnixNano()
+	out := make(map[K]V, len(c.items))
+	for key, item := range c.items {
+		if item.expiration > 0 && now > item.expiration {
+			continue
+		}
+		out[key] = item.value
+	}
+	return out
+}
+
+// Len returns the number of items currently in the cache, including any not
+// yet swept by the eviction goroutine.
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+func main() {
+	cache := NewCache[string, string]()
+	cache.Set("key1", "value1", 5*time.Second)
+	cache.Set("key2", "value2", 0) // no expiration
+
+	value, found := cache.Get("key1")
+	if found {
+		fmt.Println("key1:", value)
+	} else {
+		fmt.Println("key1 not found")
+	}
+
+	time.Sleep(6 * time.Second)
+	value, found = cache.Get("key1")
+	if found {
+		fmt.Println("key1:", value)
+	} else {
+		fmt.Println("key1 not found after expiration")
+	}
+
+	value, found = cache.Get("key2")
+	if found {
+		fmt.Println("key2:", value)
+	} else {
+		fmt.Println("key2 not found")
+	}
+
+	store := NewStore(MetaNamespaceKeyFunc)
+	store.Set(widget{Namespace: "default", Name: "gizmo"})
+	if obj, found, _ := store.Get("default/gizmo"); found {
+		fmt.Println("store default/gizmo:", obj)
+	}
+}
+
+// widget is a minimal namespaced object, used only to demonstrate Store's
+// MetaNamespaceKeyFunc above.
+type widget struct {
+	Namespace string
+	Name      string
+}
+
+func (w widget) GetNamespace() string { return w.Namespace }
+func (w widget) GetName() string      { return w.Name }
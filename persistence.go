@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RegisterType registers a concrete type that may be stored as a value in an
+// AnyCache (or any Cache[K, V] where V is an interface type). gob needs to
+// know about concrete types up front to encode/decode them through an
+// interface, so call this once per type before Save/Load.
+func RegisterType(v interface{}) {
+	gob.Register(v)
+}
+
+// persistedItem is the gob-friendly, exported mirror of CacheItem used by
+// Save/Load and the WAL; CacheItem's fields are unexported so gob can't see them.
+type persistedItem[V any] struct {
+	Value      V
+	Expiration int64
+}
+
+// Save writes every item in the cache to w using encoding/gob, including
+// each item's expiration so TTLs survive a round trip.
+func (c *Cache[K, V]) Save(w io.Writer) error {
+	c.mu.RLock()
+	snapshot := make(map[K]persistedItem[V], len(c.items))
+	for key, item := range c.items {
+		snapshot[key] = persistedItem[V]{Value: item.value, Expiration: item.expiration}
+	}
+	c.mu.RUnlock()
+	return gob.NewEncoder(w).Encode(snapshot)
+}
+
+// SaveFile writes the cache's contents to the file at path, creating or
+// truncating it as needed.
+func (c *Cache[K, V]) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Save(f)
+}
+
+// Load replaces the cache's contents with items decoded from r. If the
+// cache is bounded (NewCacheWithPolicy), the loaded set is evicted down to
+// maxItems according to the configured policy, the same as Set would.
+func (c *Cache[K, V]) Load(r io.Reader) error {
+	var snapshot map[K]persistedItem[V]
+	if err := gob.NewDecoder(r).Decode(&snapshot); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	oldItems := c.items
+	c.items = make(map[K]*CacheItem[V], len(snapshot))
+	for key, pi := range snapshot {
+		_, existed := oldItems[key]
+		c.items[key] = &CacheItem[V]{value: pi.Value, expiration: pi.Expiration}
+		if c.order != nil {
+			if existed {
+				c.order.touch(key)
+			} else {
+				c.order.add(key)
+			}
+		}
+	}
+	evicted := c.evictExcessLocked()
+	c.mu.Unlock()
+	c.reportEvicted(evicted, EvictionReasonSize)
+	return nil
+}
+
+// LoadFile replaces the cache's contents with items decoded from the file at path.
+func (c *Cache[K, V]) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Load(f)
+}
+
+// WAL record operations.
+const (
+	walOpSet byte = iota
+	walOpDelete
+)
+
+// walRecord is the gob-encoded shape of a single WAL entry.
+type walRecord[K comparable, V any] struct {
+	Op         byte
+	Key        K
+	Value      V
+	Expiration int64
+}
+
+// NewCacheWithWAL creates a Cache backed by a write-ahead log at path: every
+// Set/Delete is appended as a length-prefixed gob record so the cache's
+// contents survive a restart. If path already exists, its log is replayed
+// to rebuild the in-memory state and then compacted down to the minimal set
+// of records needed to reconstruct it.
+func NewCacheWithWAL[K comparable, V any](path string) (*Cache[K, V], error) {
+	c := NewCache[K, V]()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	c.wal = f
+	if err := c.replayWAL(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := c.compactWAL(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// appendWAL appends a single length-prefixed gob record to the WAL file.
+func (c *Cache[K, V]) appendWAL(op byte, key K, value V, expiration int64) error {
+	c.walMu.Lock()
+	defer c.walMu.Unlock()
+	return writeWALRecord(c.wal, walRecord[K, V]{Op: op, Key: key, Value: value, Expiration: expiration})
+}
+
+// writeWALRecord gob-encodes rec and writes it to w as a uint32 length
+// prefix followed by the encoded bytes, so replayWAL can frame records
+// without a delimiter.
+func writeWALRecord[K comparable, V any](w io.Writer, rec walRecord[K, V]) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(rec); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint32(buf.Len())); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// replayWAL reads every record from the start of c.wal and applies it
+// directly to c.items, rebuilding whatever state was logged before restart.
+// If the cache is bounded (NewCacheWithPolicy), the replayed set is evicted
+// down to maxItems according to the configured policy, the same as Set would.
+func (c *Cache[K, V]) replayWAL() error {
+	c.mu.Lock()
+	if _, err := c.wal.Seek(0, io.SeekStart); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	for {
+		var length uint32
+		if err := binary.Read(c.wal, binary.LittleEndian, &length); err != nil {
+			if err == io.EOF {
+				break
+			}
+			c.mu.Unlock()
+			return err
+		}
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(c.wal, buf); err != nil {
+			c.mu.Unlock()
+			return err
+		}
+		var rec walRecord[K, V]
+		if err := gob.NewDecoder(bytes.NewReader(buf)).Decode(&rec); err != nil {
+			c.mu.Unlock()
+			return fmt.Errorf("wal: decode record: %w", err)
+		}
+		switch rec.Op {
+		case walOpSet:
+			_, exists := c.items[rec.Key]
+			c.items[rec.Key] = &CacheItem[V]{value: rec.Value, expiration: rec.Expiration}
+			if c.order != nil {
+				if exists {
+					c.order.touch(rec.Key)
+				} else {
+					c.order.add(rec.Key)
+				}
+			}
+		case walOpDelete:
+			delete(c.items, rec.Key)
+			if c.order != nil {
+				c.order.remove(rec.Key)
+			}
+		}
+	}
+	evicted := c.evictExcessLocked()
+	c.mu.Unlock()
+	c.reportEvicted(evicted, EvictionReasonSize)
+	return nil
+}
+
+// compactWAL rewrites the WAL so it contains exactly one Set record per
+// item currently in the cache, dropping deletes and superseded updates that
+// replayWAL just folded into c.items.
+func (c *Cache[K, V]) compactWAL() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.walMu.Lock()
+	defer c.walMu.Unlock()
+	if err := c.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := c.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	for key, item := range c.items {
+		if err := writeWALRecord(c.wal, walRecord[K, V]{Op: walOpSet, Key: key, Value: item.value, Expiration: item.expiration}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheWALRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+
+	c1, err := NewCacheWithWAL[string, string](path)
+	if err != nil {
+		t.Fatalf("NewCacheWithWAL: %v", err)
+	}
+	c1.Set("a", "1", 0)
+	c1.Set("b", "2", 0)
+	c1.Delete("a")
+	c1.Set("c", "3", 0)
+	c1.wal.Close()
+	c1.Stop()
+
+	c2, err := NewCacheWithWAL[string, string](path)
+	if err != nil {
+		t.Fatalf("NewCacheWithWAL (reopen): %v", err)
+	}
+	defer c2.Stop()
+	defer c2.wal.Close()
+
+	if _, found := c2.Get("a"); found {
+		t.Errorf("key %q should have stayed deleted across replay", "a")
+	}
+	if v, found := c2.Get("b"); !found || v != "2" {
+		t.Errorf("Get(%q) = %q, %v; want \"2\", true", "b", v, found)
+	}
+	if v, found := c2.Get("c"); !found || v != "3" {
+		t.Errorf("Get(%q) = %q, %v; want \"3\", true", "c", v, found)
+	}
+	if got := c2.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+}
+
+// TestCacheLoadRespectsPolicyBound guards against Load bulk-inserting a
+// snapshot straight past a cache's configured maxItems; it must evict back
+// down to the bound the same way Set does, for every eviction policy.
+func TestCacheLoadRespectsPolicyBound(t *testing.T) {
+	const maxItems = 5
+
+	for _, policy := range []EvictionPolicy{PolicyLRU, PolicyLFU, PolicyFIFO} {
+		src := NewCache[string, int]()
+		for i := 0; i < 20; i++ {
+			src.Set(fmt.Sprintf("k%d", i), i, 0)
+		}
+		var buf bytes.Buffer
+		if err := src.Save(&buf); err != nil {
+			src.Stop()
+			t.Fatalf("Save: %v", err)
+		}
+		src.Stop()
+
+		dst := NewCacheWithPolicy[string, int](maxItems, policy)
+		if err := dst.Load(&buf); err != nil {
+			dst.Stop()
+			t.Fatalf("policy %v: Load: %v", policy, err)
+		}
+		if got := dst.Len(); got > maxItems {
+			t.Errorf("policy %v: Len() = %d, want <= %d", policy, got, maxItems)
+		}
+		dst.Stop()
+	}
+}
+
+// TestCacheReplayWALPreservesOrderOnRepeatedKey guards against replayWAL
+// treating every walOpSet record as a brand new key: a key written more than
+// once before compaction must move to the front of the eviction order like
+// Set does, not leave the earlier write's node behind as an orphan node,
+// for every eviction policy. Under both LRU and LFU, "hot" (written twice)
+// outranks "a" (written once and never touched again), so "a" is the victim.
+func TestCacheReplayWALPreservesOrderOnRepeatedKey(t *testing.T) {
+	const maxItems = 2
+
+	for _, policy := range []EvictionPolicy{PolicyLRU, PolicyLFU} {
+		path := filepath.Join(t.TempDir(), "cache.wal")
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			t.Fatalf("policy %v: OpenFile: %v", policy, err)
+		}
+
+		c := NewCacheWithPolicy[string, int](maxItems, policy)
+		c.wal = f
+
+		for _, rec := range []struct {
+			key string
+			val int
+		}{
+			{"hot", 0}, {"a", 1}, {"b", 1}, {"hot", 1},
+		} {
+			if err := writeWALRecord(c.wal, walRecord[string, int]{Op: walOpSet, Key: rec.key, Value: rec.val}); err != nil {
+				f.Close()
+				c.Stop()
+				t.Fatalf("policy %v: writeWALRecord(%q): %v", policy, rec.key, err)
+			}
+		}
+
+		if err := c.replayWAL(); err != nil {
+			f.Close()
+			c.Stop()
+			t.Fatalf("policy %v: replayWAL: %v", policy, err)
+		}
+
+		if v, found := c.Get("hot"); !found || v != 1 {
+			t.Errorf(`policy %v: Get("hot") = %d, %v; want 1, true (written twice, must survive)`, policy, v, found)
+		}
+		if _, found := c.Get("a"); found {
+			t.Errorf(`policy %v: Get("a") = found; want evicted as the victim`, policy)
+		}
+		if got := c.Len(); got > maxItems {
+			t.Errorf("policy %v: Len() = %d, want <= %d", policy, got, maxItems)
+		}
+
+		f.Close()
+		c.Stop()
+	}
+}
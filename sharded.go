@@ -0,0 +1,87 @@
+package main
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// defaultShardCount is used when NewShardedCache is called with shards <= 0.
+const defaultShardCount = 32
+
+// ShardedCache splits keys across several independent AnyCache instances to
+// reduce lock contention under concurrent writes. Each shard owns its own
+// mutex and eviction goroutine.
+type ShardedCache struct {
+	shards []*AnyCache
+	mask   uint32
+}
+
+// NewShardedCache creates a ShardedCache with the given number of shards.
+// shards is rounded up to the next power of two so the shard index can be
+// computed with a mask instead of a modulo. If shards <= 0, defaultShardCount
+// is used.
+func NewShardedCache(shards int) *ShardedCache {
+	if shards <= 0 {
+		shards = defaultShardCount
+	}
+	n := nextPowerOfTwo(shards)
+	sc := &ShardedCache{
+		shards: make([]*AnyCache, n),
+		mask:   uint32(n - 1),
+	}
+	for i := range sc.shards {
+		sc.shards[i] = NewCache[string, any]()
+	}
+	return sc
+}
+
+// nextPowerOfTwo returns the smallest power of two >= n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// shardFor returns the shard responsible for key, selected with fnv-1a.
+func (sc *ShardedCache) shardFor(key string) *AnyCache {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return sc.shards[h.Sum32()&sc.mask]
+}
+
+// Set adds a new item to the appropriate shard with an optional expiration duration.
+func (sc *ShardedCache) Set(key string, value interface{}, duration time.Duration) {
+	sc.shardFor(key).Set(key, value, duration)
+}
+
+// Get retrieves an item from the appropriate shard.
+func (sc *ShardedCache) Get(key string) (interface{}, bool) {
+	return sc.shardFor(key).Get(key)
+}
+
+// Delete removes an item from the appropriate shard.
+func (sc *ShardedCache) Delete(key string) {
+	sc.shardFor(key).Delete(key)
+}
+
+// Items returns a snapshot of every item across all shards, merged into a
+// single map. Keys are unique across shards so no merge conflicts occur.
+func (sc *ShardedCache) Items() map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, shard := range sc.shards {
+		for key, value := range shard.Items() {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// Stop terminates every shard's eviction goroutine. Call it when the
+// ShardedCache is no longer needed to avoid leaking the underlying tickers.
+func (sc *ShardedCache) Stop() {
+	for _, shard := range sc.shards {
+		shard.Stop()
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestShardedCacheConcurrentSetGetDelete(t *testing.T) {
+	sc := NewShardedCache(8)
+	defer sc.Stop()
+
+	const n = 200
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			sc.Set(key, i, 0)
+			v, found := sc.Get(key)
+			if !found || v != i {
+				t.Errorf("Get(%q) = %v, %v; want %d, true", key, v, found, i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	items := sc.Items()
+	if len(items) != n {
+		t.Fatalf("Items() returned %d entries, want %d", len(items), n)
+	}
+
+	var delWg sync.WaitGroup
+	for i := 0; i < n; i += 2 {
+		key := fmt.Sprintf("key-%d", i)
+		delWg.Add(1)
+		go func() {
+			defer delWg.Done()
+			sc.Delete(key)
+		}()
+	}
+	delWg.Wait()
+
+	items = sc.Items()
+	if len(items) != n/2 {
+		t.Fatalf("Items() after deletes returned %d entries, want %d", len(items), n/2)
+	}
+	for i := 0; i < n; i += 2 {
+		key := fmt.Sprintf("key-%d", i)
+		if _, found := sc.Get(key); found {
+			t.Errorf("Get(%q) found after Delete", key)
+		}
+	}
+}
+
+func TestNewShardedCacheRoundsUpToPowerOfTwo(t *testing.T) {
+	sc := NewShardedCache(5)
+	defer sc.Stop()
+	if got := len(sc.shards); got != 8 {
+		t.Errorf("len(shards) = %d, want 8", got)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeyFunc derives a string key for obj, letting a Store index arbitrary
+// object types instead of requiring callers to pre-compute string keys.
+type KeyFunc func(obj interface{}) (string, error)
+
+// Store is the minimal interface a cache backend must satisfy to hold
+// arbitrary objects keyed via a KeyFunc. MemStore is the in-memory
+// implementation, built on top of Cache; alternative backends (BoltDB,
+// Redis, ...) can satisfy the same interface.
+type Store interface {
+	// Set indexes obj under the key produced by the Store's KeyFunc.
+	Set(obj interface{}) error
+	// Get returns the object stored under key, if any.
+	Get(key string) (interface{}, bool, error)
+	// Delete removes the object stored under key, if any.
+	Delete(key string) error
+	// List returns every object currently in the store, in no particular order.
+	List() []interface{}
+	// Len returns the number of objects currently in the store.
+	Len() int
+}
+
+// namespacedObject is satisfied by objects that expose Kubernetes-style
+// namespace/name accessors, for use with MetaNamespaceKeyFunc.
+type namespacedObject interface {
+	GetNamespace() string
+	GetName() string
+}
+
+// MetaNamespaceKeyFunc derives a "namespace/name" key from obj, or just
+// "name" when the namespace is empty. It returns an error if obj does not
+// expose GetNamespace/GetName accessors.
+func MetaNamespaceKeyFunc(obj interface{}) (string, error) {
+	nsObj, ok := obj.(namespacedObject)
+	if !ok {
+		return "", fmt.Errorf("store: object of type %T has no namespace/name accessors", obj)
+	}
+	if ns := nsObj.GetNamespace(); ns != "" {
+		return ns + "/" + nsObj.GetName(), nil
+	}
+	return nsObj.GetName(), nil
+}
+
+var _ Store = (*MemStore)(nil)
+
+// MemStore is the Store implementation backed by an AnyCache, so it gets
+// TTL expiration and (when configured) bounded eviction for free instead of
+// reimplementing a second map+mutex.
+type MemStore struct {
+	cache *AnyCache
+	keyFn KeyFunc
+	ttl   time.Duration
+}
+
+// storeConfig collects the options NewStore applies before building the
+// underlying cache.
+type storeConfig struct {
+	ttl       time.Duration
+	maxItems  int
+	policy    EvictionPolicy
+	onEvicted func(key string, value interface{}, reason EvictionReason)
+}
+
+// Option configures a MemStore at construction time.
+type Option func(*storeConfig)
+
+// WithTTL expires every object written to the store after d. The zero value
+// (the default) means objects never expire on their own.
+func WithTTL(d time.Duration) Option {
+	return func(cfg *storeConfig) {
+		cfg.ttl = d
+	}
+}
+
+// WithPolicy bounds the store to maxItems objects, evicting according to
+// policy once that bound is exceeded.
+func WithPolicy(maxItems int, policy EvictionPolicy) Option {
+	return func(cfg *storeConfig) {
+		cfg.maxItems = maxItems
+		cfg.policy = policy
+	}
+}
+
+// WithOnEvicted registers a callback invoked whenever the store evicts an
+// object, whether due to TTL expiration or the configured policy.
+func WithOnEvicted(fn func(key string, value interface{}, reason EvictionReason)) Option {
+	return func(cfg *storeConfig) {
+		cfg.onEvicted = fn
+	}
+}
+
+// NewStore creates a MemStore that derives keys for Set using keyFn.
+func NewStore(keyFn KeyFunc, opts ...Option) *MemStore {
+	cfg := &storeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	var cache *AnyCache
+	if cfg.maxItems > 0 {
+		cache = NewCacheWithPolicy[string, any](cfg.maxItems, cfg.policy)
+	} else {
+		cache = NewCache[string, any]()
+	}
+	cache.onEvicted = cfg.onEvicted
+	return &MemStore{cache: cache, keyFn: keyFn, ttl: cfg.ttl}
+}
+
+// Set indexes obj under the key produced by the store's KeyFunc.
+func (s *MemStore) Set(obj interface{}) error {
+	key, err := s.keyFn(obj)
+	if err != nil {
+		return err
+	}
+	s.cache.Set(key, obj, s.ttl)
+	return nil
+}
+
+// Get returns the object stored under key, if any.
+func (s *MemStore) Get(key string) (interface{}, bool, error) {
+	obj, found := s.cache.Get(key)
+	return obj, found, nil
+}
+
+// Delete removes the object stored under key, if any.
+func (s *MemStore) Delete(key string) error {
+	s.cache.Delete(key)
+	return nil
+}
+
+// List returns every object currently in the store, in no particular order.
+func (s *MemStore) List() []interface{} {
+	items := s.cache.Items()
+	out := make([]interface{}, 0, len(items))
+	for _, obj := range items {
+		out = append(out, obj)
+	}
+	return out
+}
+
+// Len returns the number of objects currently in the store.
+func (s *MemStore) Len() int {
+	return s.cache.Len()
+}
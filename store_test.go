@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMetaNamespaceKeyFunc(t *testing.T) {
+	cases := []struct {
+		name    string
+		obj     interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "namespaced", obj: widget{Namespace: "default", Name: "gizmo"}, want: "default/gizmo"},
+		{name: "no namespace", obj: widget{Name: "gizmo"}, want: "gizmo"},
+		{name: "not namespaced", obj: 42, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MetaNamespaceKeyFunc(tc.obj)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("MetaNamespaceKeyFunc(%v) = %q, nil; want an error", tc.obj, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MetaNamespaceKeyFunc(%v) returned error: %v", tc.obj, err)
+			}
+			if got != tc.want {
+				t.Errorf("MetaNamespaceKeyFunc(%v) = %q, want %q", tc.obj, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMemStoreSetGetDeleteList(t *testing.T) {
+	s := NewStore(MetaNamespaceKeyFunc)
+	s.Set(widget{Namespace: "default", Name: "gizmo"})
+	s.Set(widget{Namespace: "default", Name: "gadget"})
+
+	obj, found, err := s.Get("default/gizmo")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !found || obj.(widget).Name != "gizmo" {
+		t.Fatalf("Get(%q) = %v, %v; want gizmo, true", "default/gizmo", obj, found)
+	}
+
+	if got := s.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+	if got := len(s.List()); got != 2 {
+		t.Fatalf("len(List()) = %d, want 2", got)
+	}
+
+	if err := s.Delete("default/gizmo"); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, found, _ := s.Get("default/gizmo"); found {
+		t.Fatalf("Get(%q) found after Delete", "default/gizmo")
+	}
+	if got := s.Len(); got != 1 {
+		t.Fatalf("Len() after Delete = %d, want 1", got)
+	}
+}
+
+func TestMemStoreSetKeyFuncError(t *testing.T) {
+	s := NewStore(MetaNamespaceKeyFunc)
+	if err := s.Set(42); err == nil {
+		t.Fatal("Set with a non-namespaced object should return an error")
+	}
+}
+
+func TestMemStoreWithPolicyBoundsSize(t *testing.T) {
+	const maxItems = 3
+	s := NewStore(MetaNamespaceKeyFunc, WithPolicy(maxItems, PolicyLRU))
+	for i := 0; i < 10; i++ {
+		s.Set(widget{Namespace: "default", Name: fmt.Sprintf("w%d", i)})
+	}
+	if got := s.Len(); got > maxItems {
+		t.Fatalf("Len() = %d, want <= %d", got, maxItems)
+	}
+}
+
+func TestMemStoreWithOnEvictedFires(t *testing.T) {
+	var evictedKeys []string
+	s := NewStore(MetaNamespaceKeyFunc,
+		WithPolicy(2, PolicyFIFO),
+		WithOnEvicted(func(key string, value interface{}, reason EvictionReason) {
+			evictedKeys = append(evictedKeys, key)
+		}),
+	)
+	s.Set(widget{Namespace: "default", Name: "a"})
+	s.Set(widget{Namespace: "default", Name: "b"})
+	s.Set(widget{Namespace: "default", Name: "c"})
+
+	if len(evictedKeys) != 1 {
+		t.Fatalf("onEvicted fired %d times, want 1", len(evictedKeys))
+	}
+	if evictedKeys[0] != "default/a" {
+		t.Errorf("evicted key = %q, want %q", evictedKeys[0], "default/a")
+	}
+}